@@ -0,0 +1,141 @@
+package unicorn
+
+import (
+	"fmt"
+	"hash/crc32"
+	"runtime"
+	"sync"
+)
+
+/*
+#include <unicorn/unicorn.h>
+*/
+import "C"
+
+// snapshotPageSize is the granularity at which Snapshot tracks dirty
+// memory. It doesn't need to match the host or guest page size; it only
+// trades off restore-time write-back cost against save-time hashing cost.
+const snapshotPageSize = 0x1000
+
+// Context is a saved CPU register state, as produced by ContextSave. It
+// wraps a uc_context handle and must be released with Close once no
+// longer needed; a finalizer releases it as a backstop, mirroring uc's
+// own Close-via-finalizer pattern.
+type Context struct {
+	ctx   *C.uc_context
+	final sync.Once
+}
+
+// ContextSave snapshots the current CPU register state into a new
+// Context, cheaper to produce and restore than a full Close+NewUnicorn
+// cycle.
+func (u *uc) ContextSave() (*Context, error) {
+	var ctx *C.uc_context
+	if ucerr := C.uc_context_alloc(u.handle, &ctx); ucerr != C.UC_ERR_OK {
+		return nil, errReturn(ucerr)
+	}
+	if ucerr := C.uc_context_save(u.handle, ctx); ucerr != C.UC_ERR_OK {
+		C.uc_context_free(ctx)
+		return nil, errReturn(ucerr)
+	}
+	c := &Context{ctx: ctx}
+	runtime.SetFinalizer(c, func(c *Context) { c.Close() })
+	return c, nil
+}
+
+// ContextRestore loads a previously saved register state back into the
+// engine.
+func (u *uc) ContextRestore(c *Context) error {
+	return errReturn(C.uc_context_restore(u.handle, c.ctx))
+}
+
+// Close releases the native context. It is safe to call more than once.
+func (c *Context) Close() (err error) {
+	c.final.Do(func() {
+		if c.ctx != nil {
+			err = errReturn(C.uc_context_free(c.ctx))
+			c.ctx = nil
+		}
+	})
+	return err
+}
+
+// Snapshot pairs a register Context with tracked dirty memory pages, so
+// fuzzers and symbolic-execution front-ends can cheaply roll an emulator
+// back to a known-good state between test cases instead of paying for
+// Close+NewUnicorn+re-map+re-write on every iteration.
+//
+// Save may be called repeatedly; each call only re-captures pages whose
+// content hash has changed since the last Save, so steady-state snapshots
+// in a tight fuzzing loop stay cheap even over a large address space.
+type Snapshot struct {
+	u      *uc
+	ctx    *Context
+	hashes map[uint64]uint32
+	saved  map[uint64][]byte
+}
+
+// NewSnapshot creates an empty Snapshot bound to this engine. Call Save
+// at least once before the first Restore.
+func (u *uc) NewSnapshot() *Snapshot {
+	return &Snapshot{u: u, hashes: make(map[uint64]uint32), saved: make(map[uint64][]byte)}
+}
+
+// Save captures the current register state and diffs mapped memory
+// against the last Save, recording only the pages that changed.
+func (s *Snapshot) Save() error {
+	ctx, err := s.u.ContextSave()
+	if err != nil {
+		return err
+	}
+	if s.ctx != nil {
+		s.ctx.Close()
+	}
+	s.ctx = ctx
+
+	regions, err := s.u.MemRegions()
+	if err != nil {
+		return err
+	}
+	for _, r := range regions {
+		base := r.Begin &^ uint64(snapshotPageSize-1)
+		for addr := base; addr <= r.End; addr += snapshotPageSize {
+			data, err := s.u.MemRead(addr, snapshotPageSize)
+			if err != nil {
+				return err
+			}
+			sum := crc32.ChecksumIEEE(data)
+			if prev, ok := s.hashes[addr]; !ok || prev != sum {
+				s.saved[addr] = data
+				s.hashes[addr] = sum
+			}
+		}
+	}
+	return nil
+}
+
+// Restore rolls the engine back to the last Save: registers via the
+// underlying Context, and every memory page whose content has diverged
+// since then.
+func (s *Snapshot) Restore() error {
+	if s.ctx == nil {
+		return fmt.Errorf("unicorn: Snapshot.Restore called before Save")
+	}
+	if err := s.u.ContextRestore(s.ctx); err != nil {
+		return err
+	}
+	for addr, data := range s.saved {
+		if err := s.u.MemWrite(addr, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close releases the Snapshot's underlying Context.
+func (s *Snapshot) Close() error {
+	if s.ctx == nil {
+		return nil
+	}
+	return s.ctx.Close()
+}
@@ -0,0 +1,155 @@
+package unicorn
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+/*
+#include <stdlib.h>
+#include <unicorn/unicorn.h>
+
+// uc_reg_read_batch and uc_reg_write_batch take a void** of per-register
+// value pointers. Building that array on the Go side would hand cgo a Go
+// pointer to Go memory that itself holds Go pointers, which panics under
+// the default cgocheck - so it's assembled here instead, over a
+// contiguous C.uint64_t array that holds no pointers of its own.
+static uc_err uc_reg_read_batch_helper(uc_engine *uc, int *regs, uint64_t *vals, int count) {
+	void **ptrs = malloc(sizeof(void *) * count);
+	int i;
+	for (i = 0; i < count; i++) {
+		ptrs[i] = &vals[i];
+	}
+	uc_err err = uc_reg_read_batch(uc, regs, ptrs, count);
+	free(ptrs);
+	return err;
+}
+
+static uc_err uc_reg_write_batch_helper(uc_engine *uc, int *regs, uint64_t *vals, int count) {
+	void **ptrs = malloc(sizeof(void *) * count);
+	int i;
+	for (i = 0; i < count; i++) {
+		ptrs[i] = &vals[i];
+	}
+	uc_err err = uc_reg_write_batch(uc, regs, ptrs, count);
+	free(ptrs);
+	return err;
+}
+*/
+import "C"
+
+// RegReadBytes reads a register of arbitrary width into a caller-sized
+// buffer. Use this instead of RegRead for registers wider than 64 bits
+// (x86 XMM/YMM/ZMM, ARM Q/V, ARM64 V) where RegRead would silently
+// truncate the value.
+func (u *uc) RegReadBytes(reg int, size int) ([]byte, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	buf := make([]byte, size)
+	ucerr := C.uc_reg_read(u.handle, C.int(reg), unsafe.Pointer(&buf[0]))
+	return buf, errReturn(ucerr)
+}
+
+// RegWriteBytes writes data verbatim into a register, as the counterpart
+// to RegReadBytes.
+func (u *uc) RegWriteBytes(reg int, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return errReturn(C.uc_reg_write(u.handle, C.int(reg), unsafe.Pointer(&data[0])))
+}
+
+// RegReadX86Xmm reads a 128-bit x86 XMM register.
+func (u *uc) RegReadX86Xmm(reg int) ([16]byte, error) {
+	var ret [16]byte
+	data, err := u.RegReadBytes(reg, 16)
+	copy(ret[:], data)
+	return ret, err
+}
+
+// RegWriteX86Xmm writes a 128-bit x86 XMM register.
+func (u *uc) RegWriteX86Xmm(reg int, value [16]byte) error {
+	return u.RegWriteBytes(reg, value[:])
+}
+
+// RegReadX86Ymm reads a 256-bit x86 YMM register.
+func (u *uc) RegReadX86Ymm(reg int) ([32]byte, error) {
+	var ret [32]byte
+	data, err := u.RegReadBytes(reg, 32)
+	copy(ret[:], data)
+	return ret, err
+}
+
+// RegWriteX86Ymm writes a 256-bit x86 YMM register.
+func (u *uc) RegWriteX86Ymm(reg int, value [32]byte) error {
+	return u.RegWriteBytes(reg, value[:])
+}
+
+// RegReadX86Zmm reads a 512-bit x86 ZMM register.
+func (u *uc) RegReadX86Zmm(reg int) ([64]byte, error) {
+	var ret [64]byte
+	data, err := u.RegReadBytes(reg, 64)
+	copy(ret[:], data)
+	return ret, err
+}
+
+// RegWriteX86Zmm writes a 512-bit x86 ZMM register.
+func (u *uc) RegWriteX86Zmm(reg int, value [64]byte) error {
+	return u.RegWriteBytes(reg, value[:])
+}
+
+// RegReadArm64V reads a 128-bit ARM64 V register.
+func (u *uc) RegReadArm64V(reg int) ([16]byte, error) {
+	var ret [16]byte
+	data, err := u.RegReadBytes(reg, 16)
+	copy(ret[:], data)
+	return ret, err
+}
+
+// RegWriteArm64V writes a 128-bit ARM64 V register.
+func (u *uc) RegWriteArm64V(reg int, value [16]byte) error {
+	return u.RegWriteBytes(reg, value[:])
+}
+
+// RegReadBatch reads many registers in a single cgo call via
+// uc_reg_read_batch, which is considerably cheaper than calling RegRead
+// once per register from tracing hooks that snapshot dozens of them per
+// instruction.
+func (u *uc) RegReadBatch(regs []int) ([]uint64, error) {
+	if len(regs) == 0 {
+		return nil, nil
+	}
+	cregs := make([]C.int, len(regs))
+	for i, r := range regs {
+		cregs[i] = C.int(r)
+	}
+	vals := make([]C.uint64_t, len(regs))
+	ucerr := C.uc_reg_read_batch_helper(u.handle, &cregs[0], &vals[0], C.int(len(regs)))
+	ret := make([]uint64, len(regs))
+	for i, v := range vals {
+		ret[i] = uint64(v)
+	}
+	return ret, errReturn(ucerr)
+}
+
+// RegWriteBatch writes many registers in a single cgo call via
+// uc_reg_write_batch. regs and vals must be the same length.
+func (u *uc) RegWriteBatch(regs []int, vals []uint64) error {
+	if len(regs) != len(vals) {
+		return fmt.Errorf("unicorn: RegWriteBatch: regs and vals must be the same length (%d != %d)", len(regs), len(vals))
+	}
+	if len(regs) == 0 {
+		return nil
+	}
+	cregs := make([]C.int, len(regs))
+	for i, r := range regs {
+		cregs[i] = C.int(r)
+	}
+	cvals := make([]C.uint64_t, len(vals))
+	for i, v := range vals {
+		cvals[i] = C.uint64_t(v)
+	}
+	ucerr := C.uc_reg_write_batch_helper(u.handle, &cregs[0], &cvals[0], C.int(len(regs)))
+	return errReturn(ucerr)
+}
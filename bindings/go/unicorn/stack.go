@@ -0,0 +1,257 @@
+package unicorn
+
+/*
+#include <unicorn/unicorn.h>
+*/
+import "C"
+
+// ArchInfo describes the pointer width and endianness of an emulated
+// architecture, as needed by the stack helpers below.
+type ArchInfo struct {
+	PtrSize   int
+	BigEndian bool
+}
+
+// Arch reports the pointer width and endianness this Unicorn instance
+// was opened with.
+func (u *uc) Arch() ArchInfo {
+	info := ArchInfo{PtrSize: 8, BigEndian: u.mode&int(C.UC_MODE_BIG_ENDIAN) != 0}
+	switch u.arch {
+	case int(C.UC_ARCH_ARM):
+		info.PtrSize = 4
+	case int(C.UC_ARCH_ARM64):
+		info.PtrSize = 8
+	case int(C.UC_ARCH_X86):
+		switch {
+		case u.mode&int(C.UC_MODE_64) != 0:
+			info.PtrSize = 8
+		case u.mode&int(C.UC_MODE_16) != 0:
+			info.PtrSize = 2
+		default:
+			info.PtrSize = 4
+		}
+	case int(C.UC_ARCH_MIPS), int(C.UC_ARCH_PPC), int(C.UC_ARCH_SPARC):
+		if u.mode&int(C.UC_MODE_64) != 0 {
+			info.PtrSize = 8
+		} else {
+			info.PtrSize = 4
+		}
+	}
+	return info
+}
+
+// spReg returns the register id of the architecture's stack pointer.
+func (u *uc) spReg() (int, error) {
+	switch u.arch {
+	case int(C.UC_ARCH_X86):
+		switch {
+		case u.mode&int(C.UC_MODE_64) != 0:
+			return int(C.UC_X86_REG_RSP), nil
+		case u.mode&int(C.UC_MODE_16) != 0:
+			return int(C.UC_X86_REG_SP), nil
+		default:
+			return int(C.UC_X86_REG_ESP), nil
+		}
+	case int(C.UC_ARCH_ARM):
+		return int(C.UC_ARM_REG_SP), nil
+	case int(C.UC_ARCH_ARM64):
+		return int(C.UC_ARM64_REG_SP), nil
+	case int(C.UC_ARCH_MIPS):
+		return int(C.UC_MIPS_REG_SP), nil
+	case int(C.UC_ARCH_PPC):
+		return int(C.UC_PPC_REG_1), nil
+	case int(C.UC_ARCH_SPARC):
+		return int(C.UC_SPARC_REG_SP), nil
+	}
+	return 0, errReturn(C.UC_ERR_ARCH)
+}
+
+func (info ArchInfo) encode(value uint64) []byte {
+	buf := make([]byte, info.PtrSize)
+	if info.BigEndian {
+		for i := 0; i < info.PtrSize; i++ {
+			buf[info.PtrSize-1-i] = byte(value >> uint(8*i))
+		}
+	} else {
+		for i := 0; i < info.PtrSize; i++ {
+			buf[i] = byte(value >> uint(8*i))
+		}
+	}
+	return buf
+}
+
+func (info ArchInfo) decode(buf []byte) uint64 {
+	var value uint64
+	if info.BigEndian {
+		for _, b := range buf {
+			value = value<<8 | uint64(b)
+		}
+	} else {
+		for i := len(buf) - 1; i >= 0; i-- {
+			value = value<<8 | uint64(buf[i])
+		}
+	}
+	return value
+}
+
+// Push decrements SP by the architecture's pointer width and writes value
+// there, mirroring what a `push` instruction does.
+func (u *uc) Push(value uint64) (uint64, error) {
+	return u.PushBytes(u.Arch().encode(value))
+}
+
+// PushBytes decrements SP by len(data) and writes data there.
+func (u *uc) PushBytes(data []byte) (uint64, error) {
+	sp, err := u.spReg()
+	if err != nil {
+		return 0, err
+	}
+	spVal, err := u.RegRead(sp)
+	if err != nil {
+		return 0, err
+	}
+	spVal -= uint64(len(data))
+	if err := u.MemWrite(spVal, data); err != nil {
+		return 0, err
+	}
+	return spVal, u.RegWrite(sp, spVal)
+}
+
+// Pop reads a pointer-width value off the top of the stack and increments
+// SP past it, mirroring what a `pop` instruction does.
+func (u *uc) Pop() (uint64, error) {
+	info := u.Arch()
+	sp, err := u.spReg()
+	if err != nil {
+		return 0, err
+	}
+	spVal, err := u.RegRead(sp)
+	if err != nil {
+		return 0, err
+	}
+	buf, err := u.MemRead(spVal, uint64(info.PtrSize))
+	if err != nil {
+		return 0, err
+	}
+	if err := u.RegWrite(sp, spVal+uint64(info.PtrSize)); err != nil {
+		return 0, err
+	}
+	return info.decode(buf), nil
+}
+
+// StackArgs reads n pointer-width values above the current top of stack,
+// as if resuming after a `call` into a function that expects its first
+// stack-passed arguments directly above the return address.
+func (u *uc) StackArgs(n int) ([]uint64, error) {
+	return u.StackArgsFrom(uint64(u.Arch().PtrSize), n)
+}
+
+// StackArgsFrom reads n pointer-width values starting offset bytes above
+// the current top of stack. Unlike StackArgs, it makes no assumption
+// about a return address occupying the first slot, so callers that trap
+// in via something other than `call` (e.g. a syscall instruction, or an
+// ABI with a fixed register-argument save area like MIPS o32) can supply
+// the right offset themselves.
+func (u *uc) StackArgsFrom(offset uint64, n int) ([]uint64, error) {
+	info := u.Arch()
+	sp, err := u.spReg()
+	if err != nil {
+		return nil, err
+	}
+	spVal, err := u.RegRead(sp)
+	if err != nil {
+		return nil, err
+	}
+	base := spVal + offset
+	ret := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		buf, err := u.MemRead(base+uint64(i*info.PtrSize), uint64(info.PtrSize))
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = info.decode(buf)
+	}
+	return ret, nil
+}
+
+// StackInit lays out argv, envp and an auxv block on the stack in the
+// order the System V ABI expects at process entry: argc, argv pointers
+// (NULL-terminated), envp pointers (NULL-terminated), then auxv, with the
+// backing strings pushed first, above all of it. argc through the envp NULL
+// terminator must stay contiguous, so alignment padding is inserted
+// below auxv instead, sized so that SP is 16-byte aligned once argc is
+// pushed. This is the boilerplate every caller doing ELF/PE process
+// bring-up would otherwise have to hand-roll.
+func (u *uc) StackInit(argv, envp []string, auxv []byte) error {
+	sp, err := u.spReg()
+	if err != nil {
+		return err
+	}
+	info := u.Arch()
+
+	pushStr := func(s string) (uint64, error) {
+		return u.PushBytes(append([]byte(s), 0))
+	}
+
+	argvPtrs := make([]uint64, len(argv))
+	for i := len(argv) - 1; i >= 0; i-- {
+		addr, err := pushStr(argv[i])
+		if err != nil {
+			return err
+		}
+		argvPtrs[i] = addr
+	}
+	envpPtrs := make([]uint64, len(envp))
+	for i := len(envp) - 1; i >= 0; i-- {
+		addr, err := pushStr(envp[i])
+		if err != nil {
+			return err
+		}
+		envpPtrs[i] = addr
+	}
+
+	if len(auxv) > 0 {
+		if _, err := u.PushBytes(auxv); err != nil {
+			return err
+		}
+	}
+
+	// argc, argv[0..]+NULL and envp[0..]+NULL form one contiguous,
+	// fixed-size block; pad between it and auxv so SP lands
+	// 16-byte aligned once that whole block, including argc, has
+	// been pushed.
+	blockWords := uint64(len(argv)) + 1 + uint64(len(envp)) + 1 + 1
+	blockSize := blockWords * uint64(info.PtrSize)
+	spVal, err := u.RegRead(sp)
+	if err != nil {
+		return err
+	}
+	target := spVal - blockSize
+	padding := target - (target &^ uint64(0xf))
+	if padding > 0 {
+		if _, err := u.PushBytes(make([]byte, padding)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := u.Push(0); err != nil {
+		return err
+	}
+	for i := len(envpPtrs) - 1; i >= 0; i-- {
+		if _, err := u.Push(envpPtrs[i]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := u.Push(0); err != nil {
+		return err
+	}
+	for i := len(argvPtrs) - 1; i >= 0; i-- {
+		if _, err := u.Push(argvPtrs[i]); err != nil {
+			return err
+		}
+	}
+
+	_, err = u.Push(uint64(len(argv)))
+	return err
+}
@@ -0,0 +1,61 @@
+package syscall
+
+import "github.com/xwings/unicorn/bindings/go/unicorn"
+
+// Prebuilt SyscallABI descriptors for the Linux syscall conventions of
+// the architectures Unicorn supports, so callers don't have to look up
+// which registers carry which argument on each one.
+var (
+	// LinuxX86ABI is the `int 0x80` convention used by 32-bit x86.
+	LinuxX86ABI = SyscallABI{
+		NumReg:   unicorn.X86_REG_EAX,
+		ArgRegs:  []int{unicorn.X86_REG_EBX, unicorn.X86_REG_ECX, unicorn.X86_REG_EDX, unicorn.X86_REG_ESI, unicorn.X86_REG_EDI, unicorn.X86_REG_EBP},
+		RetReg:   unicorn.X86_REG_EAX,
+		TrapInsn: 0x80,
+		NumArgs:  6,
+	}
+
+	// LinuxX86_64ABI is the `syscall` convention used by x86_64.
+	LinuxX86_64ABI = SyscallABI{
+		NumReg:   unicorn.X86_REG_RAX,
+		ArgRegs:  []int{unicorn.X86_REG_RDI, unicorn.X86_REG_RSI, unicorn.X86_REG_RDX, unicorn.X86_REG_R10, unicorn.X86_REG_R8, unicorn.X86_REG_R9},
+		RetReg:   unicorn.X86_REG_RAX,
+		TrapInsn: int(unicorn.X86_INS_SYSCALL),
+		NumArgs:  6,
+	}
+
+	// LinuxArmABI is the EABI `svc 0` convention used by 32-bit ARM.
+	// TrapInsn is EXCP_SWI, the intno Unicorn reports for any `svc`
+	// immediate, not the immediate itself.
+	LinuxArmABI = SyscallABI{
+		NumReg:   unicorn.ARM_REG_R7,
+		ArgRegs:  []int{unicorn.ARM_REG_R0, unicorn.ARM_REG_R1, unicorn.ARM_REG_R2, unicorn.ARM_REG_R3, unicorn.ARM_REG_R4, unicorn.ARM_REG_R5},
+		RetReg:   unicorn.ARM_REG_R0,
+		TrapInsn: 2,
+		NumArgs:  6,
+	}
+
+	// LinuxArm64ABI is the `svc 0` convention used by ARM64. TrapInsn is
+	// EXCP_SWI, shared with 32-bit ARM.
+	LinuxArm64ABI = SyscallABI{
+		NumReg:   unicorn.ARM64_REG_X8,
+		ArgRegs:  []int{unicorn.ARM64_REG_X0, unicorn.ARM64_REG_X1, unicorn.ARM64_REG_X2, unicorn.ARM64_REG_X3, unicorn.ARM64_REG_X4, unicorn.ARM64_REG_X5},
+		RetReg:   unicorn.ARM64_REG_X0,
+		TrapInsn: 2,
+		NumArgs:  6,
+	}
+
+	// LinuxMipsABI is the o32 `syscall` convention used by MIPS, where
+	// only the first four arguments travel in registers and the rest
+	// are spilled to the stack by Table.dispatch, starting above the
+	// 16-byte register-argument home area o32 always reserves.
+	// TrapInsn is EXCP_SYSCALL, the intno Unicorn reports for `syscall`.
+	LinuxMipsABI = SyscallABI{
+		NumReg:         unicorn.MIPS_REG_V0,
+		ArgRegs:        []int{unicorn.MIPS_REG_A0, unicorn.MIPS_REG_A1, unicorn.MIPS_REG_A2, unicorn.MIPS_REG_A3},
+		RetReg:         unicorn.MIPS_REG_V0,
+		TrapInsn:       17,
+		NumArgs:        6,
+		StackArgOffset: 16,
+	}
+)
@@ -0,0 +1,121 @@
+// Package syscall provides a table-driven syscall dispatcher that can be
+// installed as a Unicorn hook, so callers emulating a userland process
+// don't have to hand-roll register extraction and ABI bookkeeping for
+// every architecture they support.
+package syscall
+
+import (
+	"fmt"
+
+	"github.com/xwings/unicorn/bindings/go/unicorn"
+)
+
+// SyscallHandler implements one syscall number. args is always sized to
+// SyscallABI.NumArgs, with any arguments beyond the ABI's register count
+// already spilled in from the stack.
+type SyscallHandler func(u unicorn.Unicorn, args []uint64) (uint64, error)
+
+// SyscallABI describes how an architecture/OS pair passes syscall
+// arguments: which register holds the syscall number, which registers
+// (in order) hold up to NumArgs arguments, which register receives the
+// return value, and which trap mechanism enters the kernel.
+//
+// TrapInsn is the interrupt number HOOK_INTR reports for this arch's
+// `svc`/`int` (not the `svc`/`int` immediate itself - those can differ,
+// e.g. ARM/ARM64 report EXCP_SWI == 2 for any `svc` immediate) when the
+// table is installed with InstallIntr, or an instruction id (e.g.
+// UC_X86_INS_SYSCALL) when installed with InstallInsn.
+//
+// StackArgOffset is the byte offset from SP where stack-spilled
+// arguments (those beyond len(ArgRegs)) begin. It depends on the ABI's
+// calling convention, not on a return address - a syscall trap pushes
+// none - so it must be set explicitly per arch (e.g. MIPS o32 reserves a
+// 16-byte register-argument home area below the spilled args).
+type SyscallABI struct {
+	NumReg         int
+	ArgRegs        []int
+	RetReg         int
+	TrapInsn       int
+	NumArgs        int
+	StackArgOffset uint64
+}
+
+// Table holds a syscall number -> handler mapping bound to a fixed ABI.
+type Table struct {
+	ABI      SyscallABI
+	Handlers map[int]SyscallHandler
+}
+
+// NewTable creates an empty syscall table for the given ABI.
+func NewTable(abi SyscallABI) *Table {
+	return &Table{ABI: abi, Handlers: make(map[int]SyscallHandler)}
+}
+
+// Register installs the handler for a single syscall number, overwriting
+// any handler previously registered for it.
+func (t *Table) Register(num int, h SyscallHandler) {
+	t.Handlers[num] = h
+}
+
+// dispatch reads the syscall number and arguments per the ABI, spilling
+// to the stack for any arguments beyond the ABI's register count, invokes
+// the registered handler, and writes the result back to RetReg.
+func (t *Table) dispatch(u unicorn.Unicorn) error {
+	numVal, err := u.RegRead(t.ABI.NumReg)
+	if err != nil {
+		return err
+	}
+	num := int(numVal)
+
+	args := make([]uint64, t.ABI.NumArgs)
+	inReg := len(t.ABI.ArgRegs)
+	if inReg > t.ABI.NumArgs {
+		inReg = t.ABI.NumArgs
+	}
+	for i := 0; i < inReg; i++ {
+		if args[i], err = u.RegRead(t.ABI.ArgRegs[i]); err != nil {
+			return err
+		}
+	}
+	if spill := t.ABI.NumArgs - inReg; spill > 0 {
+		stackArgs, err := u.StackArgsFrom(t.ABI.StackArgOffset, spill)
+		if err != nil {
+			return err
+		}
+		copy(args[inReg:], stackArgs)
+	}
+
+	handler, ok := t.Handlers[num]
+	if !ok {
+		return fmt.Errorf("syscall: no handler registered for syscall %d", num)
+	}
+	ret, err := handler(u, args)
+	if err != nil {
+		return err
+	}
+	return u.RegWrite(t.ABI.RetReg, ret)
+}
+
+// InstallIntr installs the table as a HOOK_INTR callback, dispatching
+// only when the interrupt number Unicorn reports matches the ABI's
+// TrapInsn. This is the mechanism used by ARM/ARM64/MIPS (`svc`/`syscall`
+// raising a software interrupt) and 32-bit x86 (`int 0x80`).
+func (t *Table) InstallIntr(u unicorn.Unicorn) (unicorn.Hook, error) {
+	cb := func(u unicorn.Unicorn, intno uint32) {
+		if int(intno) != t.ABI.TrapInsn {
+			return
+		}
+		t.dispatch(u)
+	}
+	return u.HookAdd(unicorn.HOOK_INTR, cb, 1, 0)
+}
+
+// InstallInsn installs the table as a HOOK_INSN callback trapping the
+// instruction id given by the ABI's TrapInsn (e.g. UC_X86_INS_SYSCALL),
+// the mechanism x86_64 uses to enter the kernel.
+func (t *Table) InstallInsn(u unicorn.Unicorn) (unicorn.Hook, error) {
+	cb := func(u unicorn.Unicorn) {
+		t.dispatch(u)
+	}
+	return u.HookAdd(unicorn.HOOK_INSN, cb, 1, 0, t.ABI.TrapInsn)
+}
@@ -44,6 +44,29 @@ type Unicorn interface {
 	RegWrite(reg int, value uint64) error
 	RegReadMmr(reg int) (*X86Mmr, error)
 	RegWriteMmr(reg int, value *X86Mmr) error
+	RegReadBytes(reg int, size int) ([]byte, error)
+	RegWriteBytes(reg int, data []byte) error
+	RegReadX86Xmm(reg int) ([16]byte, error)
+	RegWriteX86Xmm(reg int, value [16]byte) error
+	RegReadX86Ymm(reg int) ([32]byte, error)
+	RegWriteX86Ymm(reg int, value [32]byte) error
+	RegReadX86Zmm(reg int) ([64]byte, error)
+	RegWriteX86Zmm(reg int, value [64]byte) error
+	RegReadArm64V(reg int) ([16]byte, error)
+	RegWriteArm64V(reg int, value [16]byte) error
+	RegReadBatch(regs []int) ([]uint64, error)
+	RegWriteBatch(regs []int, vals []uint64) error
+	Arch() ArchInfo
+	Push(value uint64) (uint64, error)
+	PushBytes(data []byte) (uint64, error)
+	Pop() (uint64, error)
+	StackArgs(n int) ([]uint64, error)
+	StackArgsFrom(offset uint64, n int) ([]uint64, error)
+	StackInit(argv, envp []string, auxv []byte) error
+	ContextSave() (*Context, error)
+	ContextRestore(c *Context) error
+	NewSnapshot() *Snapshot
+	MemMapMMIO(addr, size uint64, readCb MMIOReadFunc, writeCb MMIOWriteFunc) error
 	Start(begin, until uint64) error
 	StartWithOptions(begin, until uint64, options *UcOptions) error
 	Stop() error
@@ -56,6 +79,8 @@ type Unicorn interface {
 type uc struct {
 	handle *C.uc_engine
 	final  sync.Once
+	arch   int
+	mode   int
 }
 
 type UcOptions struct {
@@ -72,7 +97,7 @@ func NewUnicorn(arch, mode int) (Unicorn, error) {
 	if ucerr := C.uc_open(C.uc_arch(arch), C.uc_mode(mode), &handle); ucerr != ERR_OK {
 		return nil, UcError(ucerr)
 	}
-	u := &uc{handle: handle}
+	u := &uc{handle: handle, arch: arch, mode: mode}
 	runtime.SetFinalizer(u, func(u *uc) { u.Close() })
 	return u, nil
 }